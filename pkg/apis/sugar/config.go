@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sugar
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ConfigName is the name of the configmap that holds the sugar
+	// controller's configuration.
+	ConfigName = "config-sugar"
+)
+
+// Config is the configuration for the sugar controller, driving which
+// namespaces are selected for injection of default resources (Broker,
+// Channel, ApiServerSource, ...).
+type Config struct {
+	// NamespaceSelector specifies a LabelSelector which
+	// determines which namespaces should have a default broker injected.
+	// A nil NamespaceSelector matches no namespaces, while an empty one
+	// (`&metav1.LabelSelector{}`) matches all namespaces.
+	NamespaceSelector *metav1.LabelSelector `json:"namespace-selector,omitempty"`
+}
+
+// NewConfigFromMap creates a Config from the supplied configmap data.
+func NewConfigFromMap(data map[string]string) (*Config, error) {
+	nc := &Config{}
+
+	if nsSelector, ok := data["namespace-selector"]; ok {
+		selector := &metav1.LabelSelector{}
+		if err := yaml.Unmarshal([]byte(nsSelector), selector); err != nil {
+			return nil, err
+		}
+		nc.NamespaceSelector = selector
+	}
+
+	return nc, nil
+}
+
+// NewConfigFromConfigMap creates a Config from the supplied ConfigMap.
+func NewConfigFromConfigMap(config *corev1.ConfigMap) (*Config, error) {
+	return NewConfigFromMap(config.Data)
+}
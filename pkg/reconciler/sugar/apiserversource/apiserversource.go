@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserversource
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
+	sugarconfig "knative.dev/eventing/pkg/apis/sugar"
+	"knative.dev/eventing/pkg/client/clientset/versioned"
+	sourceslisters "knative.dev/eventing/pkg/client/listers/sources/v1"
+	"knative.dev/eventing/pkg/reconciler/sugar/common"
+	"knative.dev/eventing/pkg/reconciler/sugar/resources"
+
+	pkgreconciler "knative.dev/pkg/reconciler"
+)
+
+// Reconciler provisions the default, namespace-scoped ApiServerSource for
+// namespaces selected by the sugar controller's NamespaceSelector, and
+// removes it again once a namespace falls out of selection.
+type Reconciler struct {
+	sourcesClientSet versioned.Interface
+
+	apiServerSourceLister sourceslisters.ApiServerSourceLister
+}
+
+// ReconcileKind implements the injection reconciler contract for core/v1 Namespaces.
+func (r *Reconciler) ReconcileKind(ctx context.Context, ns *corev1.Namespace) pkgreconciler.Event {
+	cfg := sugarconfig.FromContext(ctx)
+
+	enabled, err := common.NamespaceSelected(cfg, ns.Labels)
+	if err != nil {
+		return err
+	}
+
+	return common.Reconcile(ctx, ns, enabled, &maker{
+		client: r.sourcesClientSet,
+		lister: r.apiServerSourceLister,
+	})
+}
+
+// maker is the common.Maker for the default ApiServerSource.
+type maker struct {
+	client versioned.Interface
+	lister sourceslisters.ApiServerSourceLister
+}
+
+func (m *maker) Kind() string        { return "ApiServerSource" }
+func (m *maker) DisplayName() string { return "sources.knative.dev ApiServerSource" }
+
+func (m *maker) Get(namespace string) (metav1.Object, error) {
+	source, err := m.lister.ApiServerSources(namespace).Get(resources.DefaultApiServerSourceName)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return source, err
+}
+
+func (m *maker) Make(namespace string) metav1.Object {
+	return resources.MakeApiServerSource(namespace, resources.DefaultApiServerSourceName, resources.DefaultBrokerName)
+}
+
+func (m *maker) Create(ctx context.Context, obj metav1.Object) error {
+	_, err := m.client.SourcesV1().ApiServerSources(obj.GetNamespace()).Create(ctx, obj.(*sourcesv1.ApiServerSource), metav1.CreateOptions{})
+	return err
+}
+
+func (m *maker) Delete(ctx context.Context, namespace, name string) error {
+	err := m.client.SourcesV1().ApiServerSources(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
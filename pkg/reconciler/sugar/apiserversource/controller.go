@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserversource
+
+import (
+	"context"
+
+	eventingclient "knative.dev/eventing/pkg/client/injection/client"
+	apiserversourceinformer "knative.dev/eventing/pkg/client/injection/informers/sources/v1/apiserversource"
+	"knative.dev/eventing/pkg/reconciler/sugar/common"
+
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+)
+
+// NewController returns a new controller that reconciles Namespaces,
+// provisioning the default ApiServerSource the sugar controller auto-injects
+// for namespaces selected by config-sugar.
+func NewController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+	apiServerSourceInformer := apiserversourceinformer.Get(ctx)
+
+	r := &Reconciler{
+		sourcesClientSet:      eventingclient.Get(ctx),
+		apiServerSourceLister: apiServerSourceInformer.Lister(),
+	}
+
+	impl := common.NewNamespaceController(ctx, cmw, r)
+	common.WatchForDeletes(impl, apiServerSourceInformer)
+	return impl
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	sugarconfig "knative.dev/eventing/pkg/apis/sugar"
+	"knative.dev/eventing/pkg/client/clientset/versioned"
+	messaginglisters "knative.dev/eventing/pkg/client/listers/messaging/v1"
+	"knative.dev/eventing/pkg/reconciler/sugar/common"
+	"knative.dev/eventing/pkg/reconciler/sugar/resources"
+
+	pkgreconciler "knative.dev/pkg/reconciler"
+)
+
+// Reconciler provisions the default Channel for namespaces selected by the
+// sugar controller's NamespaceSelector, and removes it again once a
+// namespace falls out of selection.
+type Reconciler struct {
+	messagingClientSet versioned.Interface
+
+	channelLister messaginglisters.ChannelLister
+}
+
+// ReconcileKind implements the injection reconciler contract for core/v1 Namespaces.
+func (r *Reconciler) ReconcileKind(ctx context.Context, ns *corev1.Namespace) pkgreconciler.Event {
+	cfg := sugarconfig.FromContext(ctx)
+
+	enabled, err := common.NamespaceSelected(cfg, ns.Labels)
+	if err != nil {
+		return err
+	}
+
+	return common.Reconcile(ctx, ns, enabled, &maker{
+		client: r.messagingClientSet,
+		lister: r.channelLister,
+	})
+}
+
+// maker is the common.Maker for the default Channel.
+type maker struct {
+	client versioned.Interface
+	lister messaginglisters.ChannelLister
+}
+
+func (m *maker) Kind() string        { return "Channel" }
+func (m *maker) DisplayName() string { return "messaging.knative.dev Channel" }
+
+func (m *maker) Get(namespace string) (metav1.Object, error) {
+	ch, err := m.lister.Channels(namespace).Get(resources.DefaultChannelName)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return ch, err
+}
+
+func (m *maker) Make(namespace string) metav1.Object {
+	return resources.MakeChannel(namespace, resources.DefaultChannelName)
+}
+
+func (m *maker) Create(ctx context.Context, obj metav1.Object) error {
+	_, err := m.client.MessagingV1().Channels(obj.GetNamespace()).Create(ctx, obj.(*messagingv1.Channel), metav1.CreateOptions{})
+	return err
+}
+
+func (m *maker) Delete(ctx context.Context, namespace, name string) error {
+	err := m.client.MessagingV1().Channels(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	sugarconfig "knative.dev/eventing/pkg/apis/sugar"
+	fakeeventingclient "knative.dev/eventing/pkg/client/injection/client/fake"
+	"knative.dev/eventing/pkg/reconciler/sugar/resources"
+	sugartesting "knative.dev/eventing/pkg/reconciler/sugar/testing"
+	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
+	namespacereconciler "knative.dev/pkg/client/injection/kube/reconciler/core/v1/namespace"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	. "knative.dev/eventing/pkg/reconciler/testing/v1"
+	. "knative.dev/pkg/reconciler/testing"
+)
+
+const testNS = "test-namespace"
+
+func TestEnabled(t *testing.T) {
+	channelEvent := Eventf(corev1.EventTypeNormal, "ChannelCreated", "Default messaging.knative.dev Channel created.")
+	ch := resources.MakeChannel(testNS, resources.DefaultChannelName)
+
+	table := TableTest{{
+		Name: "bad workqueue key",
+		Key:  "too/many/parts",
+	}, {
+		Name: "key not found",
+		Key:  "foo/not-found",
+	}, {
+		Name: "Enabled for all namespaces",
+		Objects: []runtime.Object{
+			NewNamespace(testNS),
+		},
+		Key:                     testNS,
+		SkipNamespaceValidation: true,
+		WantErr:                 false,
+		WantEvents: []string{
+			channelEvent,
+		},
+		WantCreates: []runtime.Object{
+			ch,
+		},
+		Ctx: context.WithValue(context.Background(), sugartesting.SugarConfigKey,
+			&metav1.LabelSelector{}),
+	}, {
+		Name: "Namespace enabled, channel already exists, no create",
+		Objects: []runtime.Object{
+			NewNamespace(testNS),
+			resources.MakeChannel(testNS, resources.DefaultChannelName),
+		},
+		Key:                     testNS,
+		SkipNamespaceValidation: true,
+		WantErr:                 false,
+		Ctx: context.WithValue(context.Background(), sugartesting.SugarConfigKey,
+			&metav1.LabelSelector{}),
+	}, {
+		Name: "Namespace is deleted no resources",
+		Objects: []runtime.Object{
+			NewNamespace(testNS,
+				WithNamespaceDeleted,
+			),
+		},
+		Key: testNS,
+		Ctx: context.WithValue(context.Background(), sugartesting.SugarConfigKey,
+			&metav1.LabelSelector{}),
+	},
+	}
+
+	logger := logtesting.TestLogger(t)
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
+		r := &Reconciler{
+			messagingClientSet: fakeeventingclient.Get(ctx),
+			channelLister:      listers.GetChannelLister(),
+		}
+
+		sugarCfg := &sugarconfig.Config{}
+		if ls, ok := ctx.Value(sugartesting.SugarConfigKey).(*metav1.LabelSelector); ok && ls != nil {
+			sugarCfg.NamespaceSelector = ls
+		}
+
+		return namespacereconciler.NewReconciler(ctx, logger,
+			fakekubeclient.Get(ctx), listers.GetNamespaceLister(),
+			controller.GetEventRecorder(ctx), r, controller.Options{
+				SkipStatusUpdates: true,
+				ConfigStore: &sugartesting.ConfigStore{
+					Config: sugarCfg,
+				},
+			})
+	}, false, logger))
+}
+
+func TestDisabled(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		Key:  "too/many/parts",
+	}, {
+		Name: "key not found",
+		Key:  "foo/not-found",
+	}, {
+		Name: "Disabled by default",
+		Objects: []runtime.Object{
+			NewNamespace(testNS),
+		},
+		Key:                     testNS,
+		SkipNamespaceValidation: true,
+		WantErr:                 false,
+	}, {
+		Name: "Namespace is deleted no resources",
+		Objects: []runtime.Object{
+			NewNamespace(testNS,
+				WithNamespaceDeleted,
+			),
+		},
+		Key: testNS,
+	}, {
+		Name: "Namespace no longer matches, sugar-owned channel is removed",
+		Objects: []runtime.Object{
+			NewNamespace(testNS),
+			resources.MakeChannel(testNS, resources.DefaultChannelName),
+		},
+		Key:                     testNS,
+		SkipNamespaceValidation: true,
+		WantErr:                 false,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "ChannelDeleted", "Default messaging.knative.dev Channel deleted."),
+		},
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS,
+				Verb:      "delete",
+				Resource:  messagingv1.SchemeGroupVersion.WithResource("channels"),
+			},
+			Name: resources.DefaultChannelName,
+		}},
+	}, {
+		Name: "Namespace no longer matches, unlabeled user channel is preserved",
+		Objects: []runtime.Object{
+			NewNamespace(testNS),
+			&messagingv1.Channel{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNS,
+					Name:      resources.DefaultChannelName,
+				},
+			},
+		},
+		Key:                     testNS,
+		SkipNamespaceValidation: true,
+		WantErr:                 false,
+	},
+	}
+
+	logger := logtesting.TestLogger(t)
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
+		r := &Reconciler{
+			messagingClientSet: fakeeventingclient.Get(ctx),
+			channelLister:      listers.GetChannelLister(),
+		}
+
+		sugarCfg := &sugarconfig.Config{}
+		if ls, ok := ctx.Value(sugartesting.SugarConfigKey).(*metav1.LabelSelector); ok && ls != nil {
+			sugarCfg.NamespaceSelector = ls
+		}
+
+		return namespacereconciler.NewReconciler(ctx, logger,
+			fakekubeclient.Get(ctx), listers.GetNamespaceLister(),
+			controller.GetEventRecorder(ctx), r, controller.Options{
+				SkipStatusUpdates: true,
+				ConfigStore: &sugartesting.ConfigStore{
+					Config: sugarCfg,
+				},
+			})
+	}, false, logger))
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"context"
+
+	eventingclient "knative.dev/eventing/pkg/client/injection/client"
+	channelinformer "knative.dev/eventing/pkg/client/injection/informers/messaging/v1/channel"
+	"knative.dev/eventing/pkg/reconciler/sugar/common"
+
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+)
+
+// NewController returns a new controller that reconciles Namespaces,
+// provisioning the default Channel the sugar controller auto-injects for
+// namespaces selected by config-sugar.
+func NewController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+	channelInformer := channelinformer.Get(ctx)
+
+	r := &Reconciler{
+		messagingClientSet: eventingclient.Get(ctx),
+		channelLister:      channelInformer.Lister(),
+	}
+
+	impl := common.NewNamespaceController(ctx, cmw, r)
+	common.WatchForDeletes(impl, channelInformer)
+	return impl
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+
+	"k8s.io/client-go/tools/cache"
+
+	namespaceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
+	namespacereconciler "knative.dev/pkg/client/injection/kube/reconciler/core/v1/namespace"
+
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+
+	sugarconfig "knative.dev/eventing/pkg/apis/sugar"
+)
+
+// ResourceInformer is the subset of a generated injection informer that
+// WatchForDeletes needs to wire up delete events.
+type ResourceInformer interface {
+	Informer() cache.SharedIndexInformer
+}
+
+// NewNamespaceController builds the controller.Impl wiring shared by every
+// sugar subcontroller: a config-sugar Store watching cmw, and a Namespace
+// informer that enqueues on add/update and drives r's global resync.
+//
+// Callers still need to wire up a ResourceInformer's delete events with
+// WatchForDeletes, so that a resource deleted out-of-band gets re-provisioned.
+func NewNamespaceController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+	r namespacereconciler.Interface,
+) *controller.Impl {
+	namespaceInformer := namespaceinformer.Get(ctx)
+
+	var globalResync func(interface{})
+
+	configStore := sugarconfig.NewStore(logging.FromContext(ctx).Named("config-store"), func(string, interface{}) {
+		if globalResync != nil {
+			globalResync(nil)
+		}
+	})
+	configStore.WatchConfigs(cmw)
+
+	impl := namespacereconciler.NewImpl(ctx, r, func(impl *controller.Impl) controller.Options {
+		return controller.Options{ConfigStore: configStore}
+	})
+
+	globalResync = func(interface{}) {
+		impl.GlobalResync(namespaceInformer.Informer())
+	}
+
+	namespaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    impl.Enqueue,
+		UpdateFunc: controller.PassNew(impl.Enqueue),
+	})
+
+	return impl
+}
+
+// WatchForDeletes enqueues the owning Namespace whenever a namespace-scoped
+// resource tracked by resourceInformer is deleted, so impl's Reconciler gets
+// a chance to re-provision a resource an operator deleted out-of-band.
+func WatchForDeletes(impl *controller.Impl, resourceInformer ResourceInformer) {
+	resourceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: impl.EnqueueNamespaceOf,
+	})
+}
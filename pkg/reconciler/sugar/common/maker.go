@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/eventing/pkg/reconciler/sugar/resources"
+	"knative.dev/pkg/controller"
+	pkgreconciler "knative.dev/pkg/reconciler"
+)
+
+// Maker knows how to get, build, create and delete the single default
+// resource one kind of sugar subcontroller (broker, channel,
+// apiserversource, ...) provisions for an injected namespace.
+type Maker interface {
+	// Kind is the PascalCase resource kind, used as the prefix of the
+	// "<Kind>Created"/"<Kind>Deleted" event reasons Reconcile emits.
+	Kind() string
+
+	// DisplayName names the resource in the Created/Deleted event message,
+	// e.g. "eventing.knative.dev Broker".
+	DisplayName() string
+
+	// Get returns the existing default resource in namespace, or (nil, nil)
+	// if it doesn't exist.
+	Get(namespace string) (metav1.Object, error)
+
+	// Make returns the default resource to create for namespace.
+	Make(namespace string) metav1.Object
+
+	// Create creates obj.
+	Create(ctx context.Context, obj metav1.Object) error
+
+	// Delete deletes the named resource from namespace.
+	Delete(ctx context.Context, namespace, name string) error
+}
+
+// Reconcile provisions, or garbage collects, the single default resource
+// maker manages for ns, depending on whether ns is currently selected for
+// sugar injection. This is the reconcile loop every sugar subcontroller
+// runs once per resource kind it owns; only maker differs between them.
+//
+// A sugar-managed resource is created the first time ns becomes selected,
+// and removed again once ns stops matching. A user-managed resource of the
+// same name is never touched, in either direction.
+func Reconcile(ctx context.Context, ns *corev1.Namespace, enabled bool, maker Maker) pkgreconciler.Event {
+	existing, err := maker.Get(ns.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get default %s for namespace %q: %w", maker.DisplayName(), ns.Name, err)
+	}
+
+	if !enabled {
+		if existing == nil || !resources.IsSugarManaged(existing.GetLabels()) {
+			return nil
+		}
+		if err := maker.Delete(ctx, ns.Name, existing.GetName()); err != nil {
+			return fmt.Errorf("failed to delete default %s for namespace %q: %w", maker.DisplayName(), ns.Name, err)
+		}
+		controller.GetEventRecorder(ctx).Event(ns, corev1.EventTypeNormal, maker.Kind()+"Deleted",
+			fmt.Sprintf("Default %s deleted.", maker.DisplayName()))
+		return nil
+	}
+
+	if existing != nil {
+		return nil
+	}
+
+	obj := maker.Make(ns.Name)
+	if err := maker.Create(ctx, obj); err != nil {
+		return fmt.Errorf("failed to create default %s for namespace %q: %w", maker.DisplayName(), ns.Name, err)
+	}
+	controller.GetEventRecorder(ctx).Event(ns, corev1.EventTypeNormal, maker.Kind()+"Created",
+		fmt.Sprintf("Default %s created.", maker.DisplayName()))
+	return nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package common holds the logic shared by the sugar controller's
+// per-kind namespace reconcilers (broker, channel, apiserversource, ...):
+// deciding whether a namespace is selected for injection (NamespaceSelected),
+// and provisioning or garbage collecting the single default resource each
+// reconciler owns for that namespace (Maker, Reconcile).
+package common
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	sugarconfig "knative.dev/eventing/pkg/apis/sugar"
+)
+
+// NamespaceSelected reports whether nsLabels are matched by cfg's
+// NamespaceSelector. A nil selector (no sugar Config applied, or an empty
+// Config) selects no namespaces.
+func NamespaceSelected(cfg *sugarconfig.Config, nsLabels map[string]string) (bool, error) {
+	if cfg == nil || cfg.NamespaceSelector == nil {
+		return false, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(cfg.NamespaceSelector)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse namespace selector: %w", err)
+	}
+
+	return selector.Matches(labels.Set(nsLabels)), nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"context"
+
+	"github.com/kelseyhightower/envconfig"
+
+	eventingclient "knative.dev/eventing/pkg/client/injection/client"
+	brokerinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1/broker"
+	eventpolicyinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1alpha1/eventpolicy"
+	"knative.dev/eventing/pkg/reconciler/sugar/common"
+	"knative.dev/eventing/pkg/reconciler/sugar/resources"
+
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	secretinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/secret/filtered"
+
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+)
+
+type envConfig struct {
+	// ImagePullSecretName is the name of a Secret in our own namespace that,
+	// when set, is propagated into every namespace selected for injection and
+	// attached to the default Broker's data plane ServiceAccount. It is the
+	// admin's own pre-existing registry credential, fetched with a live Get,
+	// so it need not carry the resources.SugarLabelKey=SugarLabelValue label
+	// that the propagated copies this controller creates do carry.
+	ImagePullSecretName string `envconfig:"BROKER_IMAGE_PULL_SECRET_NAME" required:"false"`
+}
+
+// NewController returns a new controller that reconciles Namespaces,
+// provisioning the resources the sugar controller auto-injects (the
+// default Broker and its EventPolicy) for namespaces selected by config-sugar.
+//
+// The binary wiring this controller up must register resources.SugarLabelSelector
+// with the filtered Secret informer factory (filtered.WithSelectors) before
+// starting injection, so that the Secret informer only caches sugar-labeled
+// Secrets instead of every Secret in the cluster.
+func NewController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+	brokerInformer := brokerinformer.Get(ctx)
+	eventPolicyInformer := eventpolicyinformer.Get(ctx)
+	secretInformer := secretinformer.Get(ctx, resources.SugarLabelSelector)
+
+	env := &envConfig{}
+	if err := envconfig.Process("", env); err != nil {
+		logging.FromContext(ctx).Panicf("unable to process BROKER_IMAGE_PULL_SECRET_NAME: %v", err)
+	}
+
+	r := &Reconciler{
+		eventingClientSet: eventingclient.Get(ctx),
+		kubeClientSet:     kubeclient.Get(ctx),
+		brokerLister:      brokerInformer.Lister(),
+		policyLister:      eventPolicyInformer.Lister(),
+		secretLister:      secretInformer.Lister(),
+		pullSecretName:    env.ImagePullSecretName,
+	}
+
+	impl := common.NewNamespaceController(ctx, cmw, r)
+	common.WatchForDeletes(impl, brokerInformer)
+	common.WatchForDeletes(impl, eventPolicyInformer)
+	common.WatchForDeletes(impl, secretInformer)
+	return impl
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	sugarconfig "knative.dev/eventing/pkg/apis/sugar"
+	"knative.dev/eventing/pkg/client/clientset/versioned"
+	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1"
+	eventingv1alpha1listers "knative.dev/eventing/pkg/client/listers/eventing/v1alpha1"
+	"knative.dev/eventing/pkg/reconciler/sugar/common"
+	"knative.dev/eventing/pkg/reconciler/sugar/resources"
+
+	"knative.dev/pkg/controller"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
+)
+
+// Reconciler reconciles Namespaces, provisioning (and garbage collecting,
+// once a namespace no longer matches) the default Broker and the resources
+// that accompany it, such as its default EventPolicy and the propagated
+// image pull secret, for namespaces selected by the sugar controller's
+// NamespaceSelector.
+type Reconciler struct {
+	eventingClientSet versioned.Interface
+	kubeClientSet     kubernetes.Interface
+
+	// listers index properties about resources
+	brokerLister eventinglisters.BrokerLister
+	policyLister eventingv1alpha1listers.EventPolicyLister
+	secretLister corev1listers.SecretLister
+
+	// pullSecretName is the name of the image pull secret, living in our own
+	// namespace, that should be propagated into every injected namespace for
+	// use by the default Broker's data plane ServiceAccount. Empty disables
+	// pull secret propagation.
+	pullSecretName string
+}
+
+// ReconcileKind implements the injection reconciler contract for core/v1 Namespaces.
+func (r *Reconciler) ReconcileKind(ctx context.Context, ns *corev1.Namespace) pkgreconciler.Event {
+	cfg := sugarconfig.FromContext(ctx)
+
+	enabled, err := common.NamespaceSelected(cfg, ns.Labels)
+	if err != nil {
+		return err
+	}
+
+	if err := common.Reconcile(ctx, ns, enabled, &brokerMaker{
+		client: r.eventingClientSet,
+		lister: r.brokerLister,
+	}); err != nil {
+		return err
+	}
+
+	if err := common.Reconcile(ctx, ns, enabled, &eventPolicyMaker{
+		client: r.eventingClientSet,
+		lister: r.policyLister,
+	}); err != nil {
+		return err
+	}
+
+	if !enabled {
+		return r.reconcilePullSecretDisabled(ctx, ns)
+	}
+
+	return r.reconcilePullSecret(ctx, ns)
+}
+
+// brokerMaker is the common.Maker for the default Broker.
+type brokerMaker struct {
+	client versioned.Interface
+	lister eventinglisters.BrokerLister
+}
+
+func (m *brokerMaker) Kind() string        { return "Broker" }
+func (m *brokerMaker) DisplayName() string { return "eventing.knative.dev Broker" }
+
+func (m *brokerMaker) Get(namespace string) (metav1.Object, error) {
+	b, err := m.lister.Brokers(namespace).Get(resources.DefaultBrokerName)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return b, err
+}
+
+func (m *brokerMaker) Make(namespace string) metav1.Object {
+	return resources.MakeBroker(namespace, resources.DefaultBrokerName)
+}
+
+func (m *brokerMaker) Create(ctx context.Context, obj metav1.Object) error {
+	_, err := m.client.EventingV1().Brokers(obj.GetNamespace()).Create(ctx, obj.(*eventingv1.Broker), metav1.CreateOptions{})
+	return err
+}
+
+func (m *brokerMaker) Delete(ctx context.Context, namespace, name string) error {
+	err := m.client.EventingV1().Brokers(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// eventPolicyMaker is the common.Maker for the default Broker's EventPolicy.
+type eventPolicyMaker struct {
+	client versioned.Interface
+	lister eventingv1alpha1listers.EventPolicyLister
+}
+
+func (m *eventPolicyMaker) Kind() string        { return "EventPolicy" }
+func (m *eventPolicyMaker) DisplayName() string { return "eventing.knative.dev EventPolicy" }
+
+func (m *eventPolicyMaker) Get(namespace string) (metav1.Object, error) {
+	p, err := m.lister.EventPolicies(namespace).Get(resources.DefaultEventPolicyName)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return p, err
+}
+
+func (m *eventPolicyMaker) Make(namespace string) metav1.Object {
+	return resources.MakeEventPolicy(namespace, resources.DefaultBrokerName)
+}
+
+func (m *eventPolicyMaker) Create(ctx context.Context, obj metav1.Object) error {
+	_, err := m.client.EventingV1alpha1().EventPolicies(obj.GetNamespace()).Create(ctx, obj.(*eventingv1alpha1.EventPolicy), metav1.CreateOptions{})
+	return err
+}
+
+func (m *eventPolicyMaker) Delete(ctx context.Context, namespace, name string) error {
+	err := m.client.EventingV1alpha1().EventPolicies(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// reconcilePullSecret copies r.pullSecretName from our own namespace into ns
+// and attaches it to the default Broker data plane ServiceAccount, so that
+// images in a private registry can be pulled without per-namespace setup.
+// It is a no-op when pull secret propagation isn't configured. The source
+// Secret is the admin's own pre-existing registry credential, so it is
+// fetched with a live Get rather than through r.secretLister, which only
+// caches the sugar-labeled copies this reconciler creates.
+func (r *Reconciler) reconcilePullSecret(ctx context.Context, ns *corev1.Namespace) error {
+	if r.pullSecretName == "" {
+		return nil
+	}
+
+	src, err := r.kubeClientSet.CoreV1().Secrets(system.Namespace()).Get(ctx, r.pullSecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get image pull secret %q: %w", r.pullSecretName, err)
+	}
+
+	if _, err := r.secretLister.Secrets(ns.Name).Get(r.pullSecretName); err == nil {
+		// Already copied.
+	} else if apierrors.IsNotFound(err) {
+		dst := resources.MakePullSecret(ns.Name, src)
+		if _, err := r.kubeClientSet.CoreV1().Secrets(ns.Name).Create(ctx, dst, metav1.CreateOptions{}); apierrors.IsAlreadyExists(err) {
+			// A Secret of this name without the sugar label, which r.secretLister
+			// doesn't cache, is already present.
+		} else if err != nil {
+			return fmt.Errorf("failed to copy image pull secret into namespace %q: %w", ns.Name, err)
+		} else {
+			controller.GetEventRecorder(ctx).Event(ns, corev1.EventTypeNormal, "PullSecretCreated",
+				"Default eventing.knative.dev ServiceAccount pull secret created.")
+		}
+	} else {
+		return fmt.Errorf("failed to get image pull secret %q in namespace %q: %w", r.pullSecretName, ns.Name, err)
+	}
+
+	return r.attachPullSecretToServiceAccount(ctx, ns)
+}
+
+func (r *Reconciler) attachPullSecretToServiceAccount(ctx context.Context, ns *corev1.Namespace) error {
+	sa, err := r.kubeClientSet.CoreV1().ServiceAccounts(ns.Name).Get(ctx, resources.BrokerServiceAccountName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		// The broker's data plane ServiceAccount isn't provisioned in this
+		// namespace (yet); nothing to attach the secret to.
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get ServiceAccount %q in namespace %q: %w", resources.BrokerServiceAccountName, ns.Name, err)
+	}
+
+	for _, s := range sa.ImagePullSecrets {
+		if s.Name == r.pullSecretName {
+			return nil
+		}
+	}
+
+	sa = sa.DeepCopy()
+	sa.ImagePullSecrets = append(sa.ImagePullSecrets, corev1.LocalObjectReference{Name: r.pullSecretName})
+	if _, err := r.kubeClientSet.CoreV1().ServiceAccounts(ns.Name).Update(ctx, sa, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to attach image pull secret to ServiceAccount %q in namespace %q: %w", resources.BrokerServiceAccountName, ns.Name, err)
+	}
+	return nil
+}
+
+// reconcilePullSecretDisabled garbage collects the pull secret copy and the
+// ServiceAccount.ImagePullSecrets entry that reconcilePullSecret provisioned
+// for ns, now that ns no longer matches the NamespaceSelector. It is a no-op
+// when pull secret propagation isn't configured.
+func (r *Reconciler) reconcilePullSecretDisabled(ctx context.Context, ns *corev1.Namespace) error {
+	if r.pullSecretName == "" {
+		return nil
+	}
+
+	if err := r.detachPullSecretFromServiceAccount(ctx, ns); err != nil {
+		return err
+	}
+
+	secret, err := r.secretLister.Secrets(ns.Name).Get(r.pullSecretName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get image pull secret %q in namespace %q: %w", r.pullSecretName, ns.Name, err)
+	}
+
+	if !resources.IsSugarManaged(secret.Labels) {
+		// A user created their own Secret of this name; leave it alone.
+		return nil
+	}
+
+	if err := r.kubeClientSet.CoreV1().Secrets(ns.Name).Delete(ctx, secret.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete image pull secret %q in namespace %q: %w", r.pullSecretName, ns.Name, err)
+	}
+	controller.GetEventRecorder(ctx).Event(ns, corev1.EventTypeNormal, "PullSecretDeleted",
+		"Default eventing.knative.dev ServiceAccount pull secret deleted.")
+	return nil
+}
+
+func (r *Reconciler) detachPullSecretFromServiceAccount(ctx context.Context, ns *corev1.Namespace) error {
+	sa, err := r.kubeClientSet.CoreV1().ServiceAccounts(ns.Name).Get(ctx, resources.BrokerServiceAccountName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		// The broker's data plane ServiceAccount isn't provisioned in this
+		// namespace (any more); nothing to detach the secret from.
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get ServiceAccount %q in namespace %q: %w", resources.BrokerServiceAccountName, ns.Name, err)
+	}
+
+	idx := -1
+	for i, s := range sa.ImagePullSecrets {
+		if s.Name == r.pullSecretName {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return nil
+	}
+
+	sa = sa.DeepCopy()
+	sa.ImagePullSecrets = append(sa.ImagePullSecrets[:idx], sa.ImagePullSecrets[idx+1:]...)
+	if _, err := r.kubeClientSet.CoreV1().ServiceAccounts(ns.Name).Update(ctx, sa, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to detach image pull secret from ServiceAccount %q in namespace %q: %w", resources.BrokerServiceAccountName, ns.Name, err)
+	}
+	return nil
+}
@@ -23,15 +23,20 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
 	v1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
 	sugarconfig "knative.dev/eventing/pkg/apis/sugar"
 	fakeeventingclient "knative.dev/eventing/pkg/client/injection/client/fake"
 	"knative.dev/eventing/pkg/reconciler/sugar/resources"
+	sugartesting "knative.dev/eventing/pkg/reconciler/sugar/testing"
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
 	namespacereconciler "knative.dev/pkg/client/injection/kube/reconciler/core/v1/namespace"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/system"
+	_ "knative.dev/pkg/system/testing"
 
 	. "knative.dev/eventing/pkg/reconciler/testing/v1"
 	. "knative.dev/pkg/reconciler/testing"
@@ -47,28 +52,24 @@ const (
 	LegacyInjectionLabelKey           = "eventing.knative.dev/injection"
 	LegacyInjectionEnabledLabelValue  = "enabled"
 	LegacyInjectionDisabledLabelValue = "disabled"
-)
-
-type key int
 
-var (
-	sugarConfigContextKey key
+	pullSecretName = "docker-registry-secret"
 )
 
-type testConfigStore struct {
-	config *sugarconfig.Config
-}
+type key int
 
-func (t *testConfigStore) ToContext(ctx context.Context) context.Context {
-	return sugarconfig.ToContext(ctx, t.config)
-}
+// pullSecretContextKey is namespace-reconciler specific, so it isn't part of
+// the shared sugartesting helpers.
+var pullSecretContextKey key = 1
 
 func TestEnabled(t *testing.T) {
 	// Events
 	brokerEvent := Eventf(corev1.EventTypeNormal, "BrokerCreated", "Default eventing.knative.dev Broker created.")
+	eventPolicyEvent := Eventf(corev1.EventTypeNormal, "EventPolicyCreated", "Default eventing.knative.dev EventPolicy created.")
 
 	// Objects
 	broker := resources.MakeBroker(testNS, resources.DefaultBrokerName)
+	eventPolicy := resources.MakeEventPolicy(testNS, resources.DefaultBrokerName)
 
 	table := TableTest{{
 		Name: "bad workqueue key",
@@ -88,11 +89,13 @@ func TestEnabled(t *testing.T) {
 		WantErr:                 false,
 		WantEvents: []string{
 			brokerEvent,
+			eventPolicyEvent,
 		},
 		WantCreates: []runtime.Object{
 			broker,
+			eventPolicy,
 		},
-		Ctx: context.WithValue(context.Background(), sugarConfigContextKey,
+		Ctx: context.WithValue(context.Background(), sugartesting.SugarConfigKey,
 			&metav1.LabelSelector{}),
 	}, {
 		Name: "Labelled namespace with expected `key` and `value`",
@@ -107,11 +110,13 @@ func TestEnabled(t *testing.T) {
 		WantErr:                 false,
 		WantEvents: []string{
 			brokerEvent,
+			eventPolicyEvent,
 		},
 		WantCreates: []runtime.Object{
 			broker,
+			eventPolicy,
 		},
-		Ctx: context.WithValue(context.Background(), sugarConfigContextKey,
+		Ctx: context.WithValue(context.Background(), sugartesting.SugarConfigKey,
 			&metav1.LabelSelector{
 				MatchExpressions: []metav1.LabelSelectorRequirement{{
 					Key:      SomeLabelKey,
@@ -131,11 +136,13 @@ func TestEnabled(t *testing.T) {
 		WantErr:                 false,
 		WantEvents: []string{
 			brokerEvent,
+			eventPolicyEvent,
 		},
 		WantCreates: []runtime.Object{
 			broker,
+			eventPolicy,
 		},
-		Ctx: context.WithValue(context.Background(), sugarConfigContextKey,
+		Ctx: context.WithValue(context.Background(), sugartesting.SugarConfigKey,
 			&metav1.LabelSelector{
 				MatchExpressions: []metav1.LabelSelectorRequirement{{
 					Key:      LegacyInjectionLabelKey,
@@ -150,18 +157,19 @@ func TestEnabled(t *testing.T) {
 			),
 		},
 		Key: testNS,
-		Ctx: context.WithValue(context.Background(), sugarConfigContextKey,
+		Ctx: context.WithValue(context.Background(), sugartesting.SugarConfigKey,
 			&metav1.LabelSelector{}),
 	}, {
-		Name: "Namespace enabled, broker exists",
+		Name: "Namespace enabled, broker and event policy exist",
 		Objects: []runtime.Object{
 			NewNamespace(testNS),
 			resources.MakeBroker(testNS, resources.DefaultBrokerName),
+			eventPolicy,
 		},
 		Key:                     testNS,
 		SkipNamespaceValidation: true,
 		WantErr:                 false,
-		Ctx: context.WithValue(context.Background(), sugarConfigContextKey,
+		Ctx: context.WithValue(context.Background(), sugartesting.SugarConfigKey,
 			&metav1.LabelSelector{}),
 	}, {
 		Name: "Namespace enabled, broker exists with no label",
@@ -173,12 +181,116 @@ func TestEnabled(t *testing.T) {
 					Name:      resources.DefaultBrokerName,
 				},
 			},
+			eventPolicy,
+		},
+		Key:                     testNS,
+		SkipNamespaceValidation: true,
+		WantErr:                 false,
+		Ctx: context.WithValue(context.Background(), sugartesting.SugarConfigKey,
+			&metav1.LabelSelector{}),
+	}, {
+		Name: "Namespace enabled, broker exists, event policy missing",
+		Objects: []runtime.Object{
+			NewNamespace(testNS),
+			resources.MakeBroker(testNS, resources.DefaultBrokerName),
 		},
 		Key:                     testNS,
 		SkipNamespaceValidation: true,
 		WantErr:                 false,
-		Ctx: context.WithValue(context.Background(), sugarConfigContextKey,
+		WantEvents: []string{
+			eventPolicyEvent,
+		},
+		WantCreates: []runtime.Object{
+			eventPolicy,
+		},
+		Ctx: context.WithValue(context.Background(), sugartesting.SugarConfigKey,
 			&metav1.LabelSelector{}),
+	}, {
+		Name: "Namespace enabled, pull secret missing in our namespace",
+		Objects: []runtime.Object{
+			NewNamespace(testNS),
+			resources.MakeBroker(testNS, resources.DefaultBrokerName),
+			eventPolicy,
+		},
+		Key:                     testNS,
+		SkipNamespaceValidation: true,
+		WantErr:                 false,
+		Ctx: context.WithValue(context.WithValue(context.Background(), sugartesting.SugarConfigKey,
+			&metav1.LabelSelector{}), pullSecretContextKey, pullSecretName),
+	}, {
+		Name: "Namespace enabled, pull secret copied into namespace",
+		Objects: []runtime.Object{
+			NewNamespace(testNS),
+			resources.MakeBroker(testNS, resources.DefaultBrokerName),
+			eventPolicy,
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: system.Namespace(),
+					Name:      pullSecretName,
+				},
+				Type: corev1.SecretTypeDockerConfigJson,
+			},
+		},
+		Key:                     testNS,
+		SkipNamespaceValidation: true,
+		WantErr:                 false,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "PullSecretCreated", "Default eventing.knative.dev ServiceAccount pull secret created."),
+		},
+		WantCreates: []runtime.Object{
+			resources.MakePullSecret(testNS, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: system.Namespace(),
+					Name:      pullSecretName,
+				},
+				Type: corev1.SecretTypeDockerConfigJson,
+			}),
+		},
+		Ctx: context.WithValue(context.WithValue(context.Background(), sugartesting.SugarConfigKey,
+			&metav1.LabelSelector{}), pullSecretContextKey, pullSecretName),
+	}, {
+		Name: "Namespace enabled, pull secret already present, no-op",
+		Objects: []runtime.Object{
+			NewNamespace(testNS),
+			resources.MakeBroker(testNS, resources.DefaultBrokerName),
+			eventPolicy,
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: system.Namespace(),
+					Name:      pullSecretName,
+				},
+				Type: corev1.SecretTypeDockerConfigJson,
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNS,
+					Name:      pullSecretName,
+				},
+				Type: corev1.SecretTypeDockerConfigJson,
+			},
+		},
+		Key:                     testNS,
+		SkipNamespaceValidation: true,
+		WantErr:                 false,
+		Ctx: context.WithValue(context.WithValue(context.Background(), sugartesting.SugarConfigKey,
+			&metav1.LabelSelector{}), pullSecretContextKey, pullSecretName),
+	}, {
+		Name: "Namespace deleted, pull secret configured, nothing leaked",
+		Objects: []runtime.Object{
+			NewNamespace(testNS,
+				WithNamespaceDeleted,
+			),
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: system.Namespace(),
+					Name:      pullSecretName,
+				},
+				Type: corev1.SecretTypeDockerConfigJson,
+			},
+		},
+		Key: testNS,
+		Ctx: context.WithValue(context.WithValue(context.Background(), sugartesting.SugarConfigKey,
+			&metav1.LabelSelector{}), pullSecretContextKey, pullSecretName),
 	},
 	}
 
@@ -186,20 +298,26 @@ func TestEnabled(t *testing.T) {
 	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
 		r := &Reconciler{
 			eventingClientSet: fakeeventingclient.Get(ctx),
+			kubeClientSet:     fakekubeclient.Get(ctx),
 			brokerLister:      listers.GetBrokerLister(),
+			policyLister:      listers.GetEventPolicyLister(),
+			secretLister:      listers.GetSecretLister(),
 		}
 
 		sugarCfg := &sugarconfig.Config{}
-		if ls, ok := ctx.Value(sugarConfigContextKey).(*metav1.LabelSelector); ok && ls != nil {
+		if ls, ok := ctx.Value(sugartesting.SugarConfigKey).(*metav1.LabelSelector); ok && ls != nil {
 			sugarCfg.NamespaceSelector = ls
 		}
+		if name, ok := ctx.Value(pullSecretContextKey).(string); ok {
+			r.pullSecretName = name
+		}
 
 		return namespacereconciler.NewReconciler(ctx, logger,
 			fakekubeclient.Get(ctx), listers.GetNamespaceLister(),
 			controller.GetEventRecorder(ctx), r, controller.Options{
 				SkipStatusUpdates: true,
-				ConfigStore: &testConfigStore{
-					config: sugarCfg,
+				ConfigStore: &sugartesting.ConfigStore{
+					Config: sugarCfg,
 				},
 			})
 	}, false, logger))
@@ -234,7 +352,7 @@ func TestDisabled(t *testing.T) {
 		Key:                     testNS,
 		SkipNamespaceValidation: true,
 		WantErr:                 false,
-		Ctx: context.WithValue(context.Background(), sugarConfigContextKey,
+		Ctx: context.WithValue(context.Background(), sugartesting.SugarConfigKey,
 			&metav1.LabelSelector{
 				MatchExpressions: []metav1.LabelSelectorRequirement{{
 					Key:      SomeLabelKey,
@@ -252,7 +370,7 @@ func TestDisabled(t *testing.T) {
 		Key:                     testNS,
 		SkipNamespaceValidation: true,
 		WantErr:                 false,
-		Ctx: context.WithValue(context.Background(), sugarConfigContextKey,
+		Ctx: context.WithValue(context.Background(), sugartesting.SugarConfigKey,
 			&metav1.LabelSelector{
 				MatchExpressions: []metav1.LabelSelectorRequirement{{
 					Key:      LegacyInjectionLabelKey,
@@ -267,28 +385,181 @@ func TestDisabled(t *testing.T) {
 			),
 		},
 		Key: testNS,
+	}, {
+		Name: "Namespace not selected, pull secret configured, not copied",
+		Objects: []runtime.Object{
+			NewNamespace(testNS),
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: system.Namespace(),
+					Name:      pullSecretName,
+				},
+				Type: corev1.SecretTypeDockerConfigJson,
+			},
+		},
+		Key:                     testNS,
+		SkipNamespaceValidation: true,
+		WantErr:                 false,
+		Ctx:                     context.WithValue(context.Background(), pullSecretContextKey, pullSecretName),
+	}, {
+		Name: "Namespace no longer matches, sugar-owned broker is removed",
+		Objects: []runtime.Object{
+			NewNamespace(testNS),
+			resources.MakeBroker(testNS, resources.DefaultBrokerName),
+		},
+		Key:                     testNS,
+		SkipNamespaceValidation: true,
+		WantErr:                 false,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "BrokerDeleted", "Default eventing.knative.dev Broker deleted."),
+		},
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS,
+				Verb:      "delete",
+				Resource:  v1.SchemeGroupVersion.WithResource("brokers"),
+			},
+			Name: resources.DefaultBrokerName,
+		}},
+	}, {
+		Name: "Namespace no longer matches, unlabeled user broker is preserved",
+		Objects: []runtime.Object{
+			NewNamespace(testNS),
+			&v1.Broker{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNS,
+					Name:      resources.DefaultBrokerName,
+				},
+			},
+		},
+		Key:                     testNS,
+		SkipNamespaceValidation: true,
+		WantErr:                 false,
+	}, {
+		Name: "Namespace no longer matches, sugar-owned event policy is removed",
+		Objects: []runtime.Object{
+			NewNamespace(testNS),
+			resources.MakeEventPolicy(testNS, resources.DefaultBrokerName),
+		},
+		Key:                     testNS,
+		SkipNamespaceValidation: true,
+		WantErr:                 false,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "EventPolicyDeleted", "Default eventing.knative.dev EventPolicy deleted."),
+		},
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS,
+				Verb:      "delete",
+				Resource:  eventingv1alpha1.SchemeGroupVersion.WithResource("eventpolicies"),
+			},
+			Name: resources.DefaultEventPolicyName,
+		}},
+	}, {
+		Name: "Namespace no longer matches, unlabeled user event policy is preserved",
+		Objects: []runtime.Object{
+			NewNamespace(testNS),
+			&eventingv1alpha1.EventPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNS,
+					Name:      resources.DefaultEventPolicyName,
+				},
+			},
+		},
+		Key:                     testNS,
+		SkipNamespaceValidation: true,
+		WantErr:                 false,
+	}, {
+		Name: "Namespace no longer matches, sugar-owned pull secret and ServiceAccount entry are removed",
+		Objects: []runtime.Object{
+			NewNamespace(testNS),
+			resources.MakePullSecret(testNS, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: system.Namespace(),
+					Name:      pullSecretName,
+				},
+				Type: corev1.SecretTypeDockerConfigJson,
+			}),
+			&corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNS,
+					Name:      resources.BrokerServiceAccountName,
+				},
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: pullSecretName}},
+			},
+		},
+		Key:                     testNS,
+		SkipNamespaceValidation: true,
+		WantErr:                 false,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "PullSecretDeleted", "Default eventing.knative.dev ServiceAccount pull secret deleted."),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS,
+				Verb:      "update",
+				Resource:  corev1.SchemeGroupVersion.WithResource("serviceaccounts"),
+			},
+			Object: &corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNS,
+					Name:      resources.BrokerServiceAccountName,
+				},
+				ImagePullSecrets: []corev1.LocalObjectReference{},
+			},
+		}},
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS,
+				Verb:      "delete",
+				Resource:  corev1.SchemeGroupVersion.WithResource("secrets"),
+			},
+			Name: pullSecretName,
+		}},
+		Ctx: context.WithValue(context.Background(), pullSecretContextKey, pullSecretName),
+	}, {
+		Name: "Namespace no longer matches, unlabeled user pull secret is preserved",
+		Objects: []runtime.Object{
+			NewNamespace(testNS),
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNS,
+					Name:      pullSecretName,
+				},
+				Type: corev1.SecretTypeDockerConfigJson,
+			},
+		},
+		Key:                     testNS,
+		SkipNamespaceValidation: true,
+		WantErr:                 false,
+		Ctx:                     context.WithValue(context.Background(), pullSecretContextKey, pullSecretName),
 	},
-	// 	TODO Test for when namespace doesn't match and broker should be removed.
 	}
 
 	logger := logtesting.TestLogger(t)
 	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
 		r := &Reconciler{
 			eventingClientSet: fakeeventingclient.Get(ctx),
+			kubeClientSet:     fakekubeclient.Get(ctx),
 			brokerLister:      listers.GetBrokerLister(),
+			policyLister:      listers.GetEventPolicyLister(),
+			secretLister:      listers.GetSecretLister(),
 		}
 
 		sugarCfg := &sugarconfig.Config{}
-		if ls, ok := ctx.Value(sugarConfigContextKey).(*metav1.LabelSelector); ok && ls != nil {
+		if ls, ok := ctx.Value(sugartesting.SugarConfigKey).(*metav1.LabelSelector); ok && ls != nil {
 			sugarCfg.NamespaceSelector = ls
 		}
+		if name, ok := ctx.Value(pullSecretContextKey).(string); ok {
+			r.pullSecretName = name
+		}
 
 		return namespacereconciler.NewReconciler(ctx, logger,
 			fakekubeclient.Get(ctx), listers.GetNamespaceLister(),
 			controller.GetEventRecorder(ctx), r, controller.Options{
 				SkipStatusUpdates: true,
-				ConfigStore: &testConfigStore{
-					config: sugarCfg,
+				ConfigStore: &sugartesting.ConfigStore{
+					Config: sugarCfg,
 				},
 			})
 	}, false, logger))
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+const (
+	// DefaultApiServerSourceName is the name given to the ApiServerSource
+	// that the sugar controller provisions for an injected namespace.
+	DefaultApiServerSourceName = "default"
+)
+
+// MakeApiServerSource creates the namespace-scoped ApiServerSource that
+// watches namespace's own Kubernetes events and forwards them to the
+// default Broker.
+func MakeApiServerSource(namespace, name, brokerName string) *sourcesv1.ApiServerSource {
+	return &sourcesv1.ApiServerSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels: map[string]string{
+				SugarLabelKey: SugarLabelValue,
+			},
+		},
+		Spec: sourcesv1.ApiServerSourceSpec{
+			EventMode: sourcesv1.ReferenceMode,
+			Resources: []sourcesv1.APIVersionKindSelector{{
+				APIVersion: "v1",
+				Kind:       "Event",
+			}},
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"kubernetes.io/metadata.name": namespace,
+				},
+			},
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: eventingv1.SchemeGroupVersion.String(),
+						Kind:       "Broker",
+						Name:       brokerName,
+					},
+				},
+			},
+		},
+	}
+}
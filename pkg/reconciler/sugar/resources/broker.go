@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "knative.dev/eventing/pkg/apis/eventing/v1"
+)
+
+const (
+	// DefaultBrokerName is the name given to the Broker that the sugar
+	// controller provisions for an injected namespace.
+	DefaultBrokerName = "default"
+
+	// SugarLabelKey is set to SugarLabelValue on every resource the sugar
+	// controller provisions, so it can tell its own resources apart from
+	// user-managed ones of the same name before garbage collecting them.
+	SugarLabelKey = "eventing.knative.dev/sugar"
+
+	// SugarLabelValue is the value of SugarLabelKey on sugar-provisioned resources.
+	SugarLabelValue = "true"
+
+	// SugarLabelSelector selects SugarLabelKey=SugarLabelValue, for use by
+	// label-filtered informers that only need to watch sugar-managed resources.
+	SugarLabelSelector = SugarLabelKey + "=" + SugarLabelValue
+)
+
+// MakeBroker creates the default Broker for namespace.
+func MakeBroker(namespace, name string) *v1.Broker {
+	return &v1.Broker{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels: map[string]string{
+				SugarLabelKey: SugarLabelValue,
+			},
+		},
+	}
+}
+
+// IsSugarManaged reports whether obj was provisioned by the sugar controller.
+func IsSugarManaged(labels map[string]string) bool {
+	return labels[SugarLabelKey] == SugarLabelValue
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+)
+
+const (
+	// DefaultChannelName is the name given to the Channel that the sugar
+	// controller provisions for an injected namespace.
+	DefaultChannelName = "default"
+)
+
+// MakeChannel creates the default Channel for namespace.
+func MakeChannel(namespace, name string) *messagingv1.Channel {
+	return &messagingv1.Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels: map[string]string{
+				SugarLabelKey: SugarLabelValue,
+			},
+		},
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+)
+
+const (
+	// DefaultEventPolicyName is the name given to the EventPolicy that the
+	// sugar controller provisions alongside the default Broker.
+	DefaultEventPolicyName = "default-broker"
+)
+
+// MakeEventPolicy creates the default EventPolicy applied to the default
+// Broker in namespace. It opts the Broker into authorization by only
+// allowing requests from ServiceAccounts in the same namespace, so admins
+// get a sane default instead of an unauthenticated ingress.
+func MakeEventPolicy(namespace, brokerName string) *v1alpha1.EventPolicy {
+	// OIDC subject of every ServiceAccount in namespace.
+	sub := "system:serviceaccount:" + namespace + ":*"
+	return &v1alpha1.EventPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      DefaultEventPolicyName,
+			Labels: map[string]string{
+				SugarLabelKey: SugarLabelValue,
+			},
+		},
+		Spec: v1alpha1.EventPolicySpec{
+			To: []v1alpha1.EventPolicySpecTo{{
+				Ref: &v1alpha1.EventPolicyToReference{
+					APIVersion: eventingv1.SchemeGroupVersion.String(),
+					Kind:       "Broker",
+					Name:       brokerName,
+				},
+			}},
+			From: []v1alpha1.EventPolicySpecFrom{{
+				Sub: &sub,
+			}},
+		},
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// BrokerServiceAccountName is the ServiceAccount used by the default
+	// Broker's data plane in an injected namespace.
+	BrokerServiceAccountName = "eventing-broker-ingress"
+)
+
+// MakePullSecret copies src into namespace, dropping the fields that
+// shouldn't follow a Secret across namespaces. The copy is labeled as
+// sugar-managed so it can be told apart from a user-managed Secret of the
+// same name, and so a label-filtered Secret informer can watch it without
+// caching every Secret in the cluster.
+func MakePullSecret(namespace string, src *corev1.Secret) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      src.Name,
+			Labels: map[string]string{
+				SugarLabelKey: SugarLabelValue,
+			},
+		},
+		Data: src.Data,
+		Type: src.Type,
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testing holds helpers shared by the sugar subcontrollers'
+// TableTests (broker, channel, apiserversource, ...).
+package testing
+
+import (
+	"context"
+
+	sugarconfig "knative.dev/eventing/pkg/apis/sugar"
+)
+
+// ConfigStore is a configmap.ConfigStore that injects a fixed sugar Config
+// into the reconcile context, standing in for the real config-sugar Store.
+type ConfigStore struct {
+	Config *sugarconfig.Config
+}
+
+// ToContext implements configmap.ConfigStore.
+func (s *ConfigStore) ToContext(ctx context.Context) context.Context {
+	return sugarconfig.ToContext(ctx, s.Config)
+}
+
+type contextKey int
+
+// SugarConfigKey is the TableRow.Ctx key a test case uses to pass a
+// *metav1.LabelSelector through to the reconciler factory, which applies it
+// as the effective config-sugar NamespaceSelector for that row.
+const SugarConfigKey contextKey = 0